@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	commonversion "github.com/prometheus/common/version"
+)
+
+// registerBuildInfo points github.com/prometheus/common/version at our
+// ldflags-populated version vars and returns a collector exposing
+// netgear_cm_exporter_build_info, the same metric node_exporter and
+// friends expose for alerting on stale deployments.
+func registerBuildInfo() prometheus.Collector {
+	commonversion.Version = version
+	commonversion.Revision = revision
+	commonversion.Branch = branch
+	commonversion.BuildUser = buildUser
+	commonversion.BuildDate = buildDate
+
+	return commonversion.NewCollector("netgear_cm_exporter")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ModemConfig describes a single modem target to scrape, as configured
+// under the top-level `modems` list.
+type ModemConfig struct {
+	Address  string            `yaml:"address"`
+	Type     string            `yaml:"type"`
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	// Labels are attached as constant labels to every metric this modem
+	// exports, in addition to the mandatory `target` label (the modem's
+	// Address) that Exporter always sets to keep multiple modems in the
+	// same registry from colliding. Configurations for multiple modems
+	// should use the same label keys here, since metrics of the same name
+	// sharing a registry must all carry the same label set.
+	Labels map[string]string `yaml:"labels"`
+}
+
+// defaultModemType is assumed for modems that don't set `type`, keeping
+// existing configurations pointed at the original Ubee UVW320B driver.
+const defaultModemType = "ubee-uvw320b"
+
+// TelemetryConfig configures the exporter's own HTTP server.
+type TelemetryConfig struct {
+	ListenAddress string `yaml:"listen_address"`
+	MetricsPath   string `yaml:"metrics_path"`
+}
+
+// Config is the top level exporter configuration.
+type Config struct {
+	Modems    []ModemConfig   `yaml:"modems"`
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+}
+
+// NewConfigFromFile reads and parses the YAML configuration file at path.
+func NewConfigFromFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		Telemetry: TelemetryConfig{
+			ListenAddress: ":9527",
+			MetricsPath:   "/metrics",
+		},
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %s", err)
+	}
+
+	return config, nil
+}
+
+// DriverType returns the configured modem type, falling back to
+// defaultModemType when the modem doesn't set one.
+func (m *ModemConfig) DriverType() string {
+	if m.Type == "" {
+		return defaultModemType
+	}
+	return m.Type
+}
+
+// FindModem returns the configured modem with the given address, for use
+// by the /probe handler when constructing an on-the-fly Exporter.
+func (c *Config) FindModem(address string) (ModemConfig, bool) {
+	for _, modem := range c.Modems {
+		if modem.Address == address {
+			return modem, true
+		}
+	}
+
+	return ModemConfig{}, false
+}
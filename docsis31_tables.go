@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// docsis31ChannelDescs groups the Prometheus descriptors for DOCSIS 3.1
+// OFDM downstream and OFDMA upstream channels, which carry the bulk of
+// throughput on modern gigabit cable connections but aren't reported by
+// the classic SC-QAM bonded channel tables.
+type docsis31ChannelDescs struct {
+	logger log.Logger
+
+	dsOfdmChannelPlcPower          *prometheus.Desc
+	dsOfdmChannelPlcLocked         *prometheus.Desc
+	dsOfdmChannelNcpMer            *prometheus.Desc
+	dsOfdmChannelRxMer             *prometheus.Desc
+	dsOfdmChannelActiveSubcarriers *prometheus.Desc
+	dsOfdmChannelCorrectableErrs   *prometheus.Desc
+	dsOfdmChannelUncorrectableErrs *prometheus.Desc
+
+	usOfdmaChannelPower             *prometheus.Desc
+	usOfdmaChannelActiveSubcarriers *prometheus.Desc
+}
+
+func newDocsis31ChannelDescs(logger log.Logger, constLabels prometheus.Labels) docsis31ChannelDescs {
+	labelNames := []string{"channel", "profile", "lock_status", "subcarrier_range", "frequency"}
+
+	return docsis31ChannelDescs{
+		logger: logger,
+
+		dsOfdmChannelPlcPower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "plc_power_dbmv"),
+			"Downstream OFDM channel PLC power in dBmV.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelPlcLocked: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "plc_locked"),
+			"Whether the downstream OFDM channel's PLC is locked.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelNcpMer: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "ncp_mer_db"),
+			"Downstream OFDM channel NCP modulation error ratio in dB.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelRxMer: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "rxmer_db"),
+			"Downstream OFDM channel average receive modulation error ratio in dB.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelActiveSubcarriers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "active_subcarriers"),
+			"Number of active subcarriers on the downstream OFDM channel.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelCorrectableErrs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "correctable_errors_total"),
+			"Downstream OFDM channel codewords corrected by FEC.",
+			labelNames, constLabels,
+		),
+		dsOfdmChannelUncorrectableErrs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_ofdm_channel", "uncorrectable_errors_total"),
+			"Downstream OFDM channel codewords uncorrectable by FEC.",
+			labelNames, constLabels,
+		),
+
+		usOfdmaChannelPower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upstream_ofdma_channel", "power_dbmv"),
+			"Upstream OFDMA channel power in dBmV.",
+			labelNames, constLabels,
+		),
+		usOfdmaChannelActiveSubcarriers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upstream_ofdma_channel", "active_subcarriers"),
+			"Number of active subcarriers on the upstream OFDMA channel.",
+			labelNames, constLabels,
+		),
+	}
+}
+
+func (d docsis31ChannelDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.dsOfdmChannelPlcPower
+	ch <- d.dsOfdmChannelPlcLocked
+	ch <- d.dsOfdmChannelNcpMer
+	ch <- d.dsOfdmChannelRxMer
+	ch <- d.dsOfdmChannelActiveSubcarriers
+	ch <- d.dsOfdmChannelCorrectableErrs
+	ch <- d.dsOfdmChannelUncorrectableErrs
+	ch <- d.usOfdmaChannelPower
+	ch <- d.usOfdmaChannelActiveSubcarriers
+}
+
+// parseOfdmChannels parses the downstream OFDM channel table, a no-op if
+// the page doesn't have one.
+func (d docsis31ChannelDescs) parseOfdmChannels(dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	dom.Find("table.dsOfdmTable tr").Each(func(i int, row *goquery.Selection) {
+		if i < 1 {
+			return // row 0 is a header
+		}
+
+		var (
+			channel          string
+			profile          string
+			lockStatus       string
+			subcarrierRange  string
+			freqMHz          string
+			plcPower         float64
+			ncpMer           float64
+			rxMer            float64
+			activeSubcarrier float64
+			correctable      float64
+			uncorrectable    float64
+		)
+
+		row.Find("td").Each(func(j int, col *goquery.Selection) {
+			text := strings.TrimSpace(col.Text())
+
+			switch j {
+			case 0:
+				channel = text
+			case 1:
+				lockStatus = text
+			case 2:
+				profile = text
+			case 3:
+				subcarrierRange = text
+			case 4:
+				{
+					var freqHZ float64
+					sscanWarn(d.logger, "downstream_ofdm_channel.frequency", text, "%f Hz", &freqHZ)
+					freqMHz = fmt.Sprintf("%0.2f Mhz", freqHZ/1e6)
+				}
+			case 5:
+				sscanWarn(d.logger, "downstream_ofdm_channel.plc_power", text, "%f dBmV", &plcPower)
+			case 6:
+				sscanWarn(d.logger, "downstream_ofdm_channel.ncp_mer", text, "%f dB", &ncpMer)
+			case 7:
+				sscanWarn(d.logger, "downstream_ofdm_channel.rxmer", text, "%f dB", &rxMer)
+			case 8:
+				sscanWarn(d.logger, "downstream_ofdm_channel.active_subcarriers", text, "%f", &activeSubcarrier)
+			case 9:
+				sscanWarn(d.logger, "downstream_ofdm_channel.correctable_errors", text, "%f", &correctable)
+			case 10:
+				sscanWarn(d.logger, "downstream_ofdm_channel.uncorrectable_errors", text, "%f", &uncorrectable)
+			}
+		})
+
+		labels := []string{channel, profile, lockStatus, subcarrierRange, freqMHz}
+
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelPlcPower, prometheus.GaugeValue, plcPower, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelPlcLocked, prometheus.GaugeValue, lockedToFloat(lockStatus), labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelNcpMer, prometheus.GaugeValue, ncpMer, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelRxMer, prometheus.GaugeValue, rxMer, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelActiveSubcarriers, prometheus.GaugeValue, activeSubcarrier, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelCorrectableErrs, prometheus.CounterValue, correctable, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsOfdmChannelUncorrectableErrs, prometheus.CounterValue, uncorrectable, labels...)
+	})
+}
+
+// parseOfdmaChannels parses the upstream OFDMA channel table, a no-op if
+// the page doesn't have one.
+func (d docsis31ChannelDescs) parseOfdmaChannels(dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	dom.Find("table.usOfdmaTable tr").Each(func(i int, row *goquery.Selection) {
+		if i < 1 {
+			return // row 0 is a header
+		}
+
+		var (
+			channel          string
+			profile          string
+			lockStatus       string
+			subcarrierRange  string
+			freqMHz          string
+			power            float64
+			activeSubcarrier float64
+		)
+
+		row.Find("td").Each(func(j int, col *goquery.Selection) {
+			text := strings.TrimSpace(col.Text())
+
+			switch j {
+			case 0:
+				channel = text
+			case 1:
+				lockStatus = text
+			case 2:
+				profile = text
+			case 3:
+				subcarrierRange = text
+			case 4:
+				{
+					var freqHZ float64
+					sscanWarn(d.logger, "upstream_ofdma_channel.frequency", text, "%f Hz", &freqHZ)
+					freqMHz = fmt.Sprintf("%0.2f Mhz", freqHZ/1e6)
+				}
+			case 5:
+				sscanWarn(d.logger, "upstream_ofdma_channel.power", text, "%f dBmV", &power)
+			case 6:
+				sscanWarn(d.logger, "upstream_ofdma_channel.active_subcarriers", text, "%f", &activeSubcarrier)
+			}
+		})
+
+		labels := []string{channel, profile, lockStatus, subcarrierRange, freqMHz}
+
+		ch <- prometheus.MustNewConstMetric(d.usOfdmaChannelPower, prometheus.GaugeValue, power, labels...)
+		ch <- prometheus.MustNewConstMetric(d.usOfdmaChannelActiveSubcarriers, prometheus.GaugeValue, activeSubcarrier, labels...)
+	})
+}
+
+func lockedToFloat(lockStatus string) float64 {
+	if strings.EqualFold(lockStatus, "Locked") {
+		return 1
+	}
+	return 0
+}
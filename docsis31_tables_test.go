@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func loadFixture(t *testing.T, path string) *goquery.Document {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return doc
+}
+
+func writeMetric(t *testing.T, m *dto.Metric, labels map[string]string, value float64) {
+	t.Helper()
+
+	got := make(map[string]string, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		got[lp.GetName()] = lp.GetValue()
+	}
+	for k, v := range labels {
+		if got[k] != v {
+			t.Errorf("label %q = %q, want %q", k, got[k], v)
+		}
+	}
+
+	var actual float64
+	switch {
+	case m.Gauge != nil:
+		actual = m.Gauge.GetValue()
+	case m.Counter != nil:
+		actual = m.Counter.GetValue()
+	default:
+		t.Fatalf("metric %v has neither Gauge nor Counter value", m)
+	}
+	if actual != value {
+		t.Errorf("value = %v, want %v", actual, value)
+	}
+}
+
+func TestParseOfdmChannels(t *testing.T) {
+	dom := loadFixture(t, "testdata/OfdmStatus.htm").Selection
+	descs := newDocsis31ChannelDescs(log.NewNopLogger(), nil)
+
+	ch := make(chan prometheus.Metric, 16)
+	descs.parseOfdmChannels(dom, ch)
+	close(ch)
+
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	const wantCount = 7 // plc_power, plc_locked, ncp_mer, rxmer, active_subcarriers, correctable, uncorrectable
+	if len(metrics) != wantCount {
+		t.Fatalf("got %d metrics, want %d", len(metrics), wantCount)
+	}
+
+	wantLabels := map[string]string{
+		"channel":          "33",
+		"profile":          "A",
+		"lock_status":      "Locked",
+		"subcarrier_range": "148-1765",
+		"frequency":        "509.00 Mhz",
+	}
+
+	wantValues := []float64{2.1, 1, 40.1, 38.5, 1880, 120, 3}
+	for i, m := range metrics {
+		var dm dto.Metric
+		if err := m.Write(&dm); err != nil {
+			t.Fatalf("failed to write metric %d: %v", i, err)
+		}
+		writeMetric(t, &dm, wantLabels, wantValues[i])
+	}
+}
+
+func TestParseOfdmaChannels(t *testing.T) {
+	dom := loadFixture(t, "testdata/OfdmStatus.htm").Selection
+	descs := newDocsis31ChannelDescs(log.NewNopLogger(), nil)
+
+	ch := make(chan prometheus.Metric, 16)
+	descs.parseOfdmaChannels(dom, ch)
+	close(ch)
+
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	const wantCount = 2 // power, active_subcarriers
+	if len(metrics) != wantCount {
+		t.Fatalf("got %d metrics, want %d", len(metrics), wantCount)
+	}
+
+	wantLabels := map[string]string{
+		"channel":          "9",
+		"profile":          "3",
+		"lock_status":      "Locked",
+		"subcarrier_range": "74-1884",
+		"frequency":        "24.30 Mhz",
+	}
+
+	wantValues := []float64{44.0, 1800}
+	for i, m := range metrics {
+		var dm dto.Metric
+		if err := m.Write(&dm); err != nil {
+			t.Fatalf("failed to write metric %d: %v", i, err)
+		}
+		writeMetric(t, &dm, wantLabels, wantValues[i])
+	}
+}
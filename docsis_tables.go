@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// docsisChannelDescs groups the Prometheus descriptors for the classic
+// SC-QAM bonded DOCSIS channels, shared by any driver whose Docsis status
+// page follows the common embedded-UI bonded-channel table layout (one
+// table each for downstream channels, downstream counters, upstream
+// channels and named counters).
+type docsisChannelDescs struct {
+	logger log.Logger
+
+	dsChannelSNR        *prometheus.Desc
+	dsChannelPower      *prometheus.Desc
+	dsCorrectableErrs   *prometheus.Desc
+	dsUncorrectableErrs *prometheus.Desc
+
+	usChannelPower      *prometheus.Desc
+	usChannelSymbolRate *prometheus.Desc
+
+	namedCounters *prometheus.Desc
+}
+
+func newDocsisChannelDescs(logger log.Logger, constLabels prometheus.Labels) docsisChannelDescs {
+	var (
+		dsLabelNames = []string{"channel", "lock_status", "modulation", "frequency"}
+		usLabelNames = []string{"channel", "lock_status", "channel_type", "frequency"}
+	)
+
+	return docsisChannelDescs{
+		logger: logger,
+
+		dsChannelSNR: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_channel", "snr_db"),
+			"Downstream channel signal to noise ratio in dB.",
+			dsLabelNames, constLabels,
+		),
+		dsChannelPower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_channel", "power_dbmv"),
+			"Downstream channel power in dBmV.",
+			dsLabelNames, constLabels,
+		),
+		dsCorrectableErrs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_channel", "correctable_errors_total"),
+			"Downstream channel correctable errors.",
+			nil, constLabels,
+		),
+		dsUncorrectableErrs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "downstream_channel", "uncorrectable_errors_total"),
+			"Downstream channel uncorrectable errors.",
+			nil, constLabels,
+		),
+
+		usChannelPower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upstream_channel", "power_dbmv"),
+			"Upstream channel power in dBmV.",
+			usLabelNames, constLabels,
+		),
+		usChannelSymbolRate: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upstream_channel", "symbol_rate"),
+			"Upstream channel symbol rate per second",
+			usLabelNames, constLabels,
+		),
+
+		namedCounters: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "timeouts_total"),
+			"Timeouts as reported by the modem.",
+			[]string{"name"}, constLabels,
+		),
+	}
+}
+
+func (d docsisChannelDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.dsChannelSNR
+	ch <- d.dsChannelPower
+	ch <- d.dsCorrectableErrs
+	ch <- d.dsUncorrectableErrs
+	ch <- d.usChannelPower
+	ch <- d.usChannelSymbolRate
+	ch <- d.namedCounters
+}
+
+// bondedChannelSelectors names the CSS selectors used to locate the four
+// tables parseBondedChannels expects to find in a Docsis status page.
+type bondedChannelSelectors struct {
+	downstream         string
+	downstreamCounters string
+	upstream           string
+	namedCounters      string
+}
+
+// defaultBondedChannelSelectors locates the four tables by their absolute
+// position on the page, the layout of Ubee/Ziggo's #main_page markup that
+// the generic driver also falls back to.
+var defaultBondedChannelSelectors = bondedChannelSelectors{
+	downstream:         "table:nth-of-type(2)",
+	downstreamCounters: "table:nth-of-type(3)",
+	upstream:           "table:nth-of-type(4)",
+	namedCounters:      "table:nth-of-type(5)",
+}
+
+// parseBondedChannels parses the classic downstream/upstream SC-QAM
+// bonded channel tables and named counters table out of dom, using
+// defaultBondedChannelSelectors to locate them.
+func (d docsisChannelDescs) parseBondedChannels(dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	d.parseBondedChannelsWithSelectors(dom, defaultBondedChannelSelectors, ch)
+}
+
+// parseBondedChannelsWithSelectors parses the classic downstream/upstream
+// SC-QAM bonded channel tables and named counters table out of dom,
+// locating each table via sel rather than assuming a fixed page layout.
+func (d docsisChannelDescs) parseBondedChannelsWithSelectors(dom *goquery.Selection, sel bondedChannelSelectors, ch chan<- prometheus.Metric) {
+	// Downstream Bonded Channels
+	dom.Find(sel.downstream + " tr").Each(func(i int, row *goquery.Selection) {
+		if i < 2 {
+			return // row 0 and 1 are headers
+		}
+
+		var (
+			channel    string
+			lockStatus string
+			modulation string
+			freqMHz    string
+			snr        float64
+			power      float64
+		)
+
+		row.Find("td").Each(func(j int, col *goquery.Selection) {
+			text := strings.TrimSpace(col.Text())
+
+			switch j {
+			case 0:
+				channel = text
+			case 1:
+				lockStatus = text
+			case 2:
+				modulation = text
+			case 3:
+				{
+					var freqHZ float64
+					sscanWarn(d.logger, "downstream_channel.frequency", text, "%f Hz", &freqHZ)
+					freqMHz = fmt.Sprintf("%0.2f Mhz", freqHZ/1e6)
+				}
+			case 4:
+				sscanWarn(d.logger, "downstream_channel.power", text, "%f dBmV", &power)
+			case 5:
+				sscanWarn(d.logger, "downstream_channel.snr", text, "%f dB", &snr)
+			}
+		})
+
+		labels := []string{channel, lockStatus, modulation, freqMHz}
+
+		ch <- prometheus.MustNewConstMetric(d.dsChannelSNR, prometheus.GaugeValue, snr, labels...)
+		ch <- prometheus.MustNewConstMetric(d.dsChannelPower, prometheus.GaugeValue, power, labels...)
+	})
+
+	// Downstream Bonded Channels - Correctables/Uncorrectables
+	dom.Find(sel.downstreamCounters + " tr:nth-of-type(2) td").Each(func(i int, col *goquery.Selection) {
+		var value int
+		sscanWarn(d.logger, "downstream_channel.error_count", strings.TrimSpace(col.Text()), "%d", &value)
+
+		switch i {
+		case 0:
+			ch <- prometheus.MustNewConstMetric(d.dsCorrectableErrs, prometheus.CounterValue, float64(value))
+		case 1:
+			ch <- prometheus.MustNewConstMetric(d.dsUncorrectableErrs, prometheus.CounterValue, float64(value))
+		}
+	})
+
+	// Upstream Bonded Channels
+	dom.Find(sel.upstream + " tr").Each(func(i int, row *goquery.Selection) {
+		if i < 2 {
+			return // row 0 and 1 are headers
+		}
+
+		var (
+			channel     string
+			lockStatus  string
+			channelType string
+			symbolRate  float64
+			freqMHz     string
+			power       float64
+		)
+
+		row.Find("td").Each(func(j int, col *goquery.Selection) {
+			text := strings.TrimSpace(col.Text())
+
+			switch j {
+			case 0:
+				channel = text
+			case 1:
+				lockStatus = text
+			case 2:
+				channelType = text
+			case 3:
+				{
+					sscanWarn(d.logger, "upstream_channel.symbol_rate", text, "%f Ksym/sec", &symbolRate)
+					symbolRate = symbolRate * 1000 // convert to sym/sec
+				}
+			case 4:
+				{
+					var freqHZ float64
+					sscanWarn(d.logger, "upstream_channel.frequency", text, "%f Hz", &freqHZ)
+					freqMHz = fmt.Sprintf("%0.2f Mhz", freqHZ/1e6)
+				}
+			case 5:
+				sscanWarn(d.logger, "upstream_channel.power", text, "%f dBmV", &power)
+			}
+		})
+
+		labels := []string{channel, lockStatus, channelType, freqMHz}
+
+		ch <- prometheus.MustNewConstMetric(d.usChannelPower, prometheus.GaugeValue, power, labels...)
+		ch <- prometheus.MustNewConstMetric(d.usChannelSymbolRate, prometheus.GaugeValue, symbolRate, labels...)
+	})
+
+	// Named Counters
+	dom.Find(sel.namedCounters + " tr").Each(func(i int, row *goquery.Selection) {
+		if i < 1 {
+			return // row 0 is a header
+		}
+
+		var (
+			name    string
+			counter int
+		)
+
+		row.Find("td").Each(func(j int, col *goquery.Selection) {
+			text := strings.TrimSpace(col.Text())
+
+			switch j {
+			case 0:
+				name = text
+			case 1:
+				{
+					sscanWarn(d.logger, "named_counter", text, "%d", &counter)
+				}
+			}
+		})
+
+		ch <- prometheus.MustNewConstMetric(d.namedCounters, prometheus.CounterValue, float64(counter), name)
+	})
+}
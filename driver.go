@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ModemDriver abstracts the vendor/firmware specific parts of scraping a
+// cable modem's status pages: how to authenticate, which pages to visit
+// afterwards, and how to turn the Docsis status page into Prometheus
+// metrics.
+type ModemDriver interface {
+	// Login authenticates c against the modem, performing whatever
+	// request/redirect dance the vendor's web UI requires.
+	Login(c *colly.Collector) error
+
+	// StatusURLs returns the status page paths that should be visited,
+	// relative to the modem's host, once Login succeeds.
+	StatusURLs() []string
+
+	// ParseDocsis extracts DOCSIS channel metrics from the status page at
+	// path (one of the paths returned by StatusURLs) and emits them on ch.
+	ParseDocsis(path string, dom *goquery.Selection, ch chan<- prometheus.Metric)
+
+	// Describe emits the driver's own metric descriptors.
+	Describe(ch chan<- *prometheus.Desc)
+}
+
+// DriverFactory constructs a ModemDriver for a modem at host, using the
+// given credentials, logging through logger. constLabels carries the
+// modem's configured extra labels, to be attached to every metric the
+// driver describes.
+type DriverFactory func(host string, credentials map[string]string, constLabels prometheus.Labels, logger log.Logger) ModemDriver
+
+var driverRegistry = map[string]DriverFactory{}
+
+// RegisterDriver makes a ModemDriver available under modemType, for
+// selection via the `type` field of a modem's configuration. It is
+// expected to be called from driver package init functions.
+func RegisterDriver(modemType string, factory DriverFactory) {
+	driverRegistry[modemType] = factory
+}
+
+// NewDriver looks up the driver registered for modemType and constructs
+// it for the given host/credentials.
+func NewDriver(modemType, host string, credentials map[string]string, constLabels prometheus.Labels, logger log.Logger) (ModemDriver, error) {
+	factory, ok := driverRegistry[modemType]
+	if !ok {
+		return nil, fmt.Errorf("unknown modem type %q", modemType)
+	}
+
+	return factory(host, credentials, constLabels, logger), nil
+}
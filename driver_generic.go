@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	RegisterDriver("generic-docsis", newGenericDocsisDriver)
+}
+
+// genericDocsisDriver is a best-effort fallback for cable modems without
+// a dedicated driver. Many embedded modem web UIs share the same
+// Basic-Auth-protected, bonded-channel-table layout on their status page,
+// so this is worth trying before forking a new driver.
+type genericDocsisDriver struct {
+	username, password string
+
+	docsisChannelDescs
+}
+
+func newGenericDocsisDriver(host string, credentials map[string]string, constLabels prometheus.Labels, logger log.Logger) ModemDriver {
+	return &genericDocsisDriver{
+		username:           credentials["loginUsername"],
+		password:           credentials["loginPassword"],
+		docsisChannelDescs: newDocsisChannelDescs(logger, constLabels),
+	}
+}
+
+// Describe implements ModemDriver.
+func (d *genericDocsisDriver) Describe(ch chan<- *prometheus.Desc) {
+	d.docsisChannelDescs.describe(ch)
+}
+
+// StatusURLs implements ModemDriver.
+func (d *genericDocsisDriver) StatusURLs() []string {
+	return []string{"/"}
+}
+
+// Login implements ModemDriver, attaching HTTP Basic Auth credentials to
+// every request.
+func (d *genericDocsisDriver) Login(c *colly.Collector) error {
+	token := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", d.username, d.password)))
+
+	c.OnRequest(func(r *colly.Request) {
+		r.Headers.Set("Authorization", "Basic "+token)
+	})
+
+	return nil
+}
+
+// ParseDocsis implements ModemDriver. StatusURLs only ever returns a
+// single path, so path is irrelevant here.
+func (d *genericDocsisDriver) ParseDocsis(path string, dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	d.parseBondedChannels(dom, ch)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	RegisterDriver("netgear-cm", newNetgearDriver)
+}
+
+// netgearBondedChannelSelectors locates the downstream/upstream bonded
+// channel tables by the `dsTable`/`usTable` ids Netgear CM-series
+// firmware gives them, rather than assuming their absolute position on
+// the page the way Ubee's #main_page markup allows. The error-counter
+// and named-counter tables aren't individually identified, so they're
+// found as the table immediately following their anchor.
+var netgearBondedChannelSelectors = bondedChannelSelectors{
+	downstream:         "table#dsTable",
+	downstreamCounters: "table#dsTable + table",
+	upstream:           "table#usTable",
+	namedCounters:      "table#usTable + table",
+}
+
+// netgearDriver drives Netgear CM-series cable modems (CM500, CM1000,
+// CM2000, ...), which serve their Docsis status page behind plain HTTP
+// Basic Auth rather than Ubee's form/redirect login.
+type netgearDriver struct {
+	username, password string
+
+	docsisChannelDescs
+	docsis31ChannelDescs
+}
+
+func newNetgearDriver(host string, credentials map[string]string, constLabels prometheus.Labels, logger log.Logger) ModemDriver {
+	return &netgearDriver{
+		username:             credentials["loginUsername"],
+		password:             credentials["loginPassword"],
+		docsisChannelDescs:   newDocsisChannelDescs(logger, constLabels),
+		docsis31ChannelDescs: newDocsis31ChannelDescs(logger, constLabels),
+	}
+}
+
+// Describe implements ModemDriver.
+func (d *netgearDriver) Describe(ch chan<- *prometheus.Desc) {
+	d.docsisChannelDescs.describe(ch)
+	d.docsis31ChannelDescs.describe(ch)
+}
+
+// StatusURLs implements ModemDriver. DocsisStatus.htm carries the classic
+// SC-QAM bonded channels, OfdmStatus.htm the DOCSIS 3.1 OFDM/OFDMA ones.
+func (d *netgearDriver) StatusURLs() []string {
+	return []string{"/DocsisStatus.htm", "/OfdmStatus.htm"}
+}
+
+// Login implements ModemDriver. There is no separate login request; every
+// request just needs to carry the Basic Auth credentials.
+func (d *netgearDriver) Login(c *colly.Collector) error {
+	token := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", d.username, d.password)))
+
+	c.OnRequest(func(r *colly.Request) {
+		r.Headers.Set("Authorization", "Basic "+token)
+	})
+
+	return nil
+}
+
+// ParseDocsis implements ModemDriver. It's invoked once per status page,
+// so it dispatches on path rather than running every parser against every
+// page: DocsisStatus.htm carries the bonded SC-QAM channel tables
+// (identified by their dsTable/usTable ids), OfdmStatus.htm the OFDM/OFDMA
+// ones.
+func (d *netgearDriver) ParseDocsis(path string, dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	switch path {
+	case "/DocsisStatus.htm":
+		d.parseBondedChannelsWithSelectors(dom, netgearBondedChannelSelectors, ch)
+	case "/OfdmStatus.htm":
+		d.parseOfdmChannels(dom, ch)
+		d.parseOfdmaChannels(dom, ch)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TestNetgearParseBondedChannels verifies that netgearBondedChannelSelectors,
+// which locate the downstream/upstream bonded channel tables by their
+// dsTable/usTable ids, find and parse every table of a fixture page that
+// carries an unrelated navigation table ahead of them.
+func TestNetgearParseBondedChannels(t *testing.T) {
+	dom := loadFixture(t, "testdata/DocsisStatus.htm").Selection
+	descs := newDocsisChannelDescs(log.NewNopLogger(), nil)
+
+	ch := make(chan prometheus.Metric, 16)
+	descs.parseBondedChannelsWithSelectors(dom, netgearBondedChannelSelectors, ch)
+	close(ch)
+
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	// Emission order follows parseBondedChannelsWithSelectors: downstream
+	// snr/power, downstream correctable/uncorrectable, upstream
+	// power/symbol rate, named counters.
+	const wantCount = 7
+	if len(metrics) != wantCount {
+		t.Fatalf("got %d metrics, want %d", len(metrics), wantCount)
+	}
+
+	dsLabels := map[string]string{
+		"channel":     "1",
+		"lock_status": "Locked",
+		"modulation":  "256QAM",
+		"frequency":   "501.00 Mhz",
+	}
+	usLabels := map[string]string{
+		"channel":      "2",
+		"lock_status":  "Locked",
+		"channel_type": "ATDMA",
+		"frequency":    "35.60 Mhz",
+	}
+	namedCounterLabels := map[string]string{
+		"name": "T3 Timeouts",
+	}
+
+	wantLabels := []map[string]string{dsLabels, dsLabels, nil, nil, usLabels, usLabels, namedCounterLabels}
+	wantValues := []float64{40.5, 1.2, 120, 4, 45.0, 5120000, 2}
+
+	for i, m := range metrics {
+		var dm dto.Metric
+		if err := m.Write(&dm); err != nil {
+			t.Fatalf("failed to write metric %d: %v", i, err)
+		}
+		writeMetric(t, &dm, wantLabels[i], wantValues[i])
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	RegisterDriver("ubee-uvw320b", newUbeeDriver)
+}
+
+// ubeeDriver drives the Ubee UVW320B, as deployed by Ziggo, through its
+// BasicCmState.asp/BasicStatus.asp/BasicFirmware.asp web UI.
+type ubeeDriver struct {
+	host        string
+	credentials map[string]string
+	logger      log.Logger
+
+	docsisChannelDescs
+}
+
+func newUbeeDriver(host string, credentials map[string]string, constLabels prometheus.Labels, logger log.Logger) ModemDriver {
+	return &ubeeDriver{
+		host:               host,
+		credentials:        credentials,
+		logger:             logger,
+		docsisChannelDescs: newDocsisChannelDescs(logger, constLabels),
+	}
+}
+
+// Describe implements ModemDriver.
+func (d *ubeeDriver) Describe(ch chan<- *prometheus.Desc) {
+	d.docsisChannelDescs.describe(ch)
+}
+
+// StatusURLs implements ModemDriver.
+func (d *ubeeDriver) StatusURLs() []string {
+	return []string{"/BasicCmState.asp", "/BasicStatus.asp", "/BasicFirmware.asp"}
+}
+
+// Login implements ModemDriver, following Ziggo's redirect-based login
+// flow:
+//
+//	POST /goform/loginMR3 -> Location: /loginMR3.asp  -> FAILURE
+//	POST /goform/loginMR3 -> Location: /RgHomeMR3.asp -> SUCCESS
+func (d *ubeeDriver) Login(c *colly.Collector) error {
+	c.RedirectHandler = func(req *http.Request, via []*http.Request) error {
+		if via[0].URL.Path == "/goform/loginMR3" {
+			switch req.URL.Path {
+			case "/RgHomeMR3.asp":
+				return nil
+			case "/loginMR3.asp":
+				level.Debug(d.logger).Log("msg", "login rejected by modem", "url", req.URL)
+				return fmt.Errorf("login failed")
+			default:
+				return fmt.Errorf("unknown login redirect: %s", req.URL)
+			}
+		}
+		return http.ErrUseLastResponse
+	}
+
+	return c.Post(fmt.Sprintf("http://%s/goform/loginMR3", d.host), d.credentials)
+}
+
+// ParseDocsis implements ModemDriver. Collect only hands it the page whose
+// navigation bar marks it as Docsis, with the bonded-channel tables under
+// #main_page, so path is irrelevant here.
+func (d *ubeeDriver) ParseDocsis(path string, dom *goquery.Selection, ch chan<- prometheus.Metric) {
+	d.parseBondedChannels(dom, ch)
+}
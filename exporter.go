@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "netgear_cm_exporter"
+
+// Exporter represents an instance of the Netgear cable modem exporter,
+// scraping a single modem through its ModemDriver.
+type Exporter struct {
+	host   string
+	driver ModemDriver
+	logger log.Logger
+
+	mu sync.Mutex
+
+	// Exporter metrics.
+	totalScrapes prometheus.Counter
+	scrapeErrors prometheus.Counter
+
+	modemUptime *prometheus.Desc
+}
+
+// NewExporter returns an instance of Exporter for the given modem
+// configuration, using the driver registered for its type.
+func NewExporter(modem ModemConfig, logger log.Logger) (*Exporter, error) {
+	// Every metric this Exporter (and its driver) owns carries a "target"
+	// const label set to the modem's address, so that two modems
+	// registered in the same registry never collide on name+labels, even
+	// when their configured Labels are identical or unset.
+	constLabels := make(prometheus.Labels, len(modem.Labels)+1)
+	for k, v := range modem.Labels {
+		constLabels[k] = v
+	}
+	constLabels["target"] = modem.Address
+
+	driver, err := NewDriver(
+		modem.DriverType(),
+		modem.Address,
+		map[string]string{"loginUsername": modem.Username, "loginPassword": modem.Password},
+		constLabels,
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		host:   modem.Address,
+		driver: driver,
+		logger: logger,
+
+		// Collection metrics.
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "status_scrapes_total",
+			Help:        "Total number of scrapes of the modem status page.",
+			ConstLabels: constLabels,
+		}),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "status_scrape_errors_total",
+			Help:        "Total number of failed scrapes of the modem status page.",
+			ConstLabels: constLabels,
+		}),
+
+		modemUptime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "uptime_seconds"),
+			"Reported uptime of the modem in seconds.",
+			[]string{"firmware"}, constLabels,
+		),
+	}, nil
+}
+
+// Describe returns Prometheus metric descriptions for the exporter
+// metrics, including those of its driver.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	// Exporter metrics.
+	ch <- e.totalScrapes.Desc()
+	ch <- e.scrapeErrors.Desc()
+	ch <- e.modemUptime
+	e.driver.Describe(ch)
+}
+
+type UptimeInfo struct {
+	uptime   time.Duration
+	firmware string
+
+	// collected is set once CollectStatus actually finds an uptime value,
+	// since not every driver's status pages expose one (the Ubee
+	// navigation-based dispatch in Collect is the only caller today).
+	collected bool
+}
+
+func (e *Exporter) CollectStatus(out *UptimeInfo, dom *goquery.Selection) {
+	// #main_page > div.table_data > table > tbody > tr:nth-child(2) > td:nth-child(2)
+	// <td>1 days 02h:22m:53s</td>
+	// <td>0 days 00h:00m:36s</td>
+
+	dom.Find("table:nth-of-type(1) tr:nth-child(2) > td:nth-child(2)").Each(func(_ int, sel *goquery.Selection) {
+		var (
+			days    int
+			hours   int8
+			minutes int8
+			seconds int8
+		)
+
+		sscanWarn(e.logger, "uptime",
+			strings.TrimSpace(sel.Text()),
+			"%d days %02dh:%02dm:%02ds",
+			&days,
+			&hours,
+			&minutes,
+			&seconds,
+		)
+
+		out.uptime = (time.Duration(days*24+int(hours)) * time.Hour) +
+			(time.Duration(minutes) * time.Minute) +
+			(time.Duration(seconds) * time.Second)
+		out.collected = true
+	})
+}
+
+func (e *Exporter) CollectFirmware(out *UptimeInfo, dom *goquery.Selection) {
+	// #main_page > div.table_data > table > tbody > tr:nth-child(3) > td:nth-child(2)
+	dom.Find("table tr:nth-child(3) > td:nth-child(2)").Each(func(_ int, sel *goquery.Selection) {
+		out.firmware = strings.TrimSpace(sel.Text())
+	})
+}
+
+// Collect runs our scrape loop, emitting each Prometheus metric and
+// reporting whether the scrape succeeded.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) bool {
+	e.totalScrapes.Inc()
+
+	c := colly.NewCollector()
+
+	uptimeInfo := &UptimeInfo{}
+
+	// OnError callback counts any errors that occur during scraping.
+	c.OnError(func(r *colly.Response, err error) {
+		level.Warn(e.logger).Log(
+			"msg", "scrape request failed",
+			"url", r.Request.URL,
+			"status_code", r.StatusCode,
+			"err", err,
+		)
+		e.scrapeErrors.Inc()
+	})
+
+	// Ubee-style templates expose a navigation bar shared across every
+	// status page, with the current page's content nested in #main_page.
+	c.OnHTML(".uuzp-contentholder", func(elem *colly.HTMLElement) {
+		elem.DOM.Find("#navigation_bar li a.current").Each(func(_ int, selection *goquery.Selection) {
+			var mainPage = elem.DOM.Find("#main_page")
+
+			switch strings.TrimSpace(selection.Text()) {
+			case "Docsis":
+				e.driver.ParseDocsis(elem.Request.URL.Path, mainPage, ch)
+			case "Status":
+				e.CollectStatus(uptimeInfo, mainPage)
+			case "Firmware":
+				e.CollectFirmware(uptimeInfo, mainPage)
+			}
+		})
+	})
+
+	// Other drivers expose their Docsis status across one or more
+	// dedicated pages, with no shared navigation chrome to key off.
+	c.OnHTML("html", func(elem *colly.HTMLElement) {
+		if elem.DOM.Find(".uuzp-contentholder").Length() == 0 {
+			e.driver.ParseDocsis(elem.Request.URL.Path, elem.DOM, ch)
+		}
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.driver.Login(c)
+	success := err == nil
+	if !success {
+		level.Warn(e.logger).Log("msg", "login failed", "err", err)
+	} else {
+		for _, url := range e.driver.StatusURLs() {
+			if err := c.Visit(fmt.Sprintf("http://%s%s", e.host, url)); err != nil {
+				success = false
+			}
+		}
+
+		// Not every driver's status pages expose an uptime/firmware page
+		// for CollectStatus to find; skip the metric rather than publish
+		// a misleading zero-uptime/empty-firmware series for those.
+		if uptimeInfo.collected {
+			ch <- prometheus.MustNewConstMetric(
+				e.modemUptime,
+				prometheus.GaugeValue,
+				float64(uptimeInfo.uptime.Seconds()),
+				uptimeInfo.firmware,
+			)
+		}
+	}
+
+	e.totalScrapes.Collect(ch)
+	e.scrapeErrors.Collect(ch)
+
+	return success
+}
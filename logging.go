@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// newLogger builds a leveled logger that writes logfmt or JSON to
+// stderr, filtered to minLevel (one of "debug", "info", "warn", "error").
+func newLogger(format, minLevel string) (log.Logger, error) {
+	var logger log.Logger
+	switch format {
+	case "json":
+		logger = log.NewJSONLogger(log.NewSyncWriter(os.Stderr))
+	case "logfmt", "":
+		logger = log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	// Caller(5) accounts for the level.NewFilter and level.Debug/Info/...
+	// wrappers between a call site and here; log.DefaultCaller's stack
+	// depth assumes no such wrapping and would point at level.go instead.
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.Caller(5))
+
+	var option level.Option
+	switch minLevel {
+	case "debug":
+		option = level.AllowDebug()
+	case "info", "":
+		option = level.AllowInfo()
+	case "warn":
+		option = level.AllowWarn()
+	case "error":
+		option = level.AllowError()
+	default:
+		return nil, fmt.Errorf("unknown log level %q", minLevel)
+	}
+
+	return level.NewFilter(logger, option), nil
+}
+
+// sscanWarn runs fmt.Sscanf, logging a warning if it scanned no fields at
+// all, since that usually means the modem's markup has drifted under us.
+func sscanWarn(logger log.Logger, field, text, format string, args ...interface{}) {
+	if n, _ := fmt.Sscanf(text, format, args...); n == 0 {
+		level.Warn(logger).Log("msg", "failed to parse field", "field", field, "text", text)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MultiExporter scrapes a set of modems concurrently, wrapping each
+// modem's Exporter with per-device scrape bookkeeping. It implements
+// prometheus.Collector and is what actually gets registered, both for
+// the static multi-device /metrics endpoint and for one-off /probe
+// requests.
+type MultiExporter struct {
+	targets map[string]*Exporter
+
+	scrapeDuration *prometheus.Desc
+	scrapeSuccess  *prometheus.Desc
+}
+
+// NewMultiExporter builds a MultiExporter for the given modems, keyed by
+// their configured address.
+func NewMultiExporter(modems []ModemConfig, logger log.Logger) *MultiExporter {
+	targets := make(map[string]*Exporter, len(modems))
+	for _, modem := range modems {
+		deviceLogger := log.With(logger, "device", modem.Address)
+
+		exporter, err := NewExporter(modem, deviceLogger)
+		if err != nil {
+			level.Error(deviceLogger).Log("msg", "skipping modem", "err", err)
+			continue
+		}
+		targets[modem.Address] = exporter
+	}
+
+	return &MultiExporter{
+		targets: targets,
+
+		scrapeDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "scrape_duration_seconds"),
+			"Time it took to scrape the modem.",
+			[]string{"device"}, nil,
+		),
+		scrapeSuccess: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "scrape_success"),
+			"Whether the scrape of the modem succeeded.",
+			[]string{"device"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *MultiExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.scrapeDuration
+	ch <- m.scrapeSuccess
+
+	for _, e := range m.targets {
+		e.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector, scraping every target modem in
+// parallel while still serializing requests to any individual modem via
+// its own Exporter mutex.
+func (m *MultiExporter) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+
+	for device, e := range m.targets {
+		wg.Add(1)
+
+		go func(device string, e *Exporter) {
+			defer wg.Done()
+
+			start := time.Now()
+			success := e.Collect(ch)
+			duration := time.Since(start).Seconds()
+
+			ch <- prometheus.MustNewConstMetric(m.scrapeDuration, prometheus.GaugeValue, duration, device)
+			ch <- prometheus.MustNewConstMetric(m.scrapeSuccess, prometheus.GaugeValue, boolToFloat(success), device)
+		}(device, e)
+	}
+
+	wg.Wait()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}